@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package FoxMNDP
+
+import "net"
+
+// setBroadcast is a no-op on platforms where golang.org/x/sys/unix's socket
+// option constants aren't available; Windows defaults SO_BROADCAST-less
+// sends to broadcast addresses to working without extra setup.
+func setBroadcast(conn net.PacketConn) error {
+	return nil
+}
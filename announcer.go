@@ -0,0 +1,191 @@
+package FoxMNDP
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ipv6LinkLocalMulticast is the well-known MNDP IPv6 multicast group.
+var ipv6LinkLocalMulticast = net.ParseIP("ff02::1")
+
+// AnnounceOptions configures the periodic MNDP announcer started by
+// StartAnnouncer.
+type AnnounceOptions struct {
+	Interval time.Duration // How often to emit an announcement. Default: 30s.
+
+	// Interfaces to announce on: a socket is opened and bound (via
+	// bindToDevice) for each named interface. Empty means a single
+	// wildcard-bound socket is used, sending on whichever interface the
+	// kernel's routing table picks.
+	Interfaces []string
+
+	Broadcast bool // Send IPv4 broadcast to 255.255.255.255. Set to true if both Broadcast and IPv6 are false.
+	IPv6      bool // Send IPv6 link-local multicast to ff02::1.
+
+	// StartTime is the device's boot time, used to compute Uptime on each
+	// tick. Default: time.Now() at the moment StartAnnouncer is called.
+	StartTime time.Time
+}
+
+// Announce sends a single well-formed MNDP packet describing d as an IPv4
+// broadcast to 255.255.255.255 on the configured port, from one
+// wildcard-bound socket. It sends no IPv6 multicast.
+func (f *FoxMNDP) Announce(d Device) error {
+	return f.announceOnce(d, AnnounceOptions{Broadcast: true})
+}
+
+// StartAnnouncer begins periodically announcing d, re-encoding its Uptime
+// on every tick, until Stop is called.
+func (f *FoxMNDP) StartAnnouncer(d Device, opts AnnounceOptions) error {
+	if opts.Interval == 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.StartTime.IsZero() {
+		opts.StartTime = time.Now()
+	}
+	if !opts.Broadcast && !opts.IPv6 {
+		opts.Broadcast = true
+	}
+
+	if err := f.announceOnce(d, opts); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.stopChan:
+				return
+			case <-ticker.C:
+				d.Uptime = time.Since(opts.StartTime)
+				if err := f.announceOnce(d, opts); err != nil {
+					f.logger.Error("failed to send announcement", "err", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// announceOnce encodes d and writes it to every destination opts asks for.
+func (f *FoxMNDP) announceOnce(d Device, opts AnnounceOptions) error {
+	packet := encodeDevice(d)
+
+	if opts.Broadcast {
+		if err := f.sendBroadcastV4(packet, opts.Interfaces); err != nil {
+			return err
+		}
+	}
+	if opts.IPv6 {
+		if err := f.sendMulticastV6(packet, opts.Interfaces); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendBroadcastV4 writes packet to 255.255.255.255 on the configured port,
+// once per name in ifaceNames, or once on the default route if ifaceNames is
+// empty.
+func (f *FoxMNDP) sendBroadcastV4(packet []byte, ifaceNames []string) error {
+	dst := &net.UDPAddr{IP: net.IPv4bcast, Port: f.options.Port}
+	return f.sendTo("udp4", dst, packet, ifaceNames)
+}
+
+// sendMulticastV6 writes packet to ff02::1 on the configured port, once per
+// name in ifaceNames, or once on the default route if ifaceNames is empty.
+func (f *FoxMNDP) sendMulticastV6(packet []byte, ifaceNames []string) error {
+	dst := &net.UDPAddr{IP: ipv6LinkLocalMulticast, Port: f.options.Port}
+	return f.sendTo("udp6", dst, packet, ifaceNames)
+}
+
+// sendTo opens a network ("udp4"/"udp6") socket per name in ifaceNames (or a
+// single wildcard socket if ifaceNames is empty), binds it with bindToDevice
+// where a name is given, and writes packet to dst on each. A bind or send
+// failure on one named interface is logged and doesn't stop the others,
+// matching how Start treats a bad interface in Options.Interfaces.
+func (f *FoxMNDP) sendTo(network string, dst net.Addr, packet []byte, ifaceNames []string) error {
+	if len(ifaceNames) == 0 {
+		return f.sendOn(network, "", packet, dst)
+	}
+	for _, name := range ifaceNames {
+		if err := f.sendOn(network, name, packet, dst); err != nil {
+			f.logger.Error("failed to send announcement", "interface", name, "err", err)
+		}
+	}
+	return nil
+}
+
+// sendOn opens a wildcard socket of the given network, optionally bound to a
+// named interface, and writes packet to dst. For udp4 it also sets
+// SO_BROADCAST, which the kernel otherwise requires before a non-root
+// process can send to a broadcast address like 255.255.255.255.
+func (f *FoxMNDP) sendOn(network, ifaceName string, packet []byte, dst net.Addr) error {
+	conn, err := net.ListenPacket(network, ":0")
+	if err != nil {
+		return fmt.Errorf("failed to open socket: %w", err)
+	}
+	defer conn.Close()
+
+	if ifaceName != "" {
+		if err := bindToDevice(conn, ifaceName); err != nil {
+			return fmt.Errorf("failed to bind socket to %s: %w", ifaceName, err)
+		}
+	}
+
+	if network == "udp4" {
+		if err := setBroadcast(conn); err != nil {
+			return fmt.Errorf("failed to set SO_BROADCAST: %w", err)
+		}
+	}
+
+	if _, err := conn.WriteTo(packet, dst); err != nil {
+		return fmt.Errorf("failed to send to %s: %w", dst, err)
+	}
+	return nil
+}
+
+// encodeDevice serializes d into a 4-byte MNDP header followed by its TLV
+// attributes, suitable for sending with sendBroadcastV4/sendMulticastV6.
+// Every TLV type registered with an encoder (see tlv.go) is considered, i.e.
+// the built-in attributes. Types added through RegisterTLV have no encoder
+// and are not written, and the raw values in d.Unknown are not written
+// either.
+func encodeDevice(d Device) []byte {
+	var buf bytes.Buffer
+
+	// 4-byte header. parsePacket only requires this to be 4 bytes long and
+	// skips it, so a reserved/zeroed header is sufficient here.
+	buf.Write([]byte{0x00, 0x00, 0x00, 0x00})
+
+	for _, t := range encodableTLVTypes() {
+		reg, ok := lookupTLV(t)
+		if !ok || reg.encode == nil {
+			continue
+		}
+		value, ok := reg.encode(&d)
+		if !ok {
+			continue
+		}
+		writeTLV(&buf, t, value)
+	}
+
+	return buf.Bytes()
+}
+
+// writeTLV appends a single Type-Length-Value attribute to buf. Empty
+// values are skipped so unset Device fields don't pollute the packet.
+func writeTLV(buf *bytes.Buffer, t uint16, value []byte) {
+	if len(value) == 0 {
+		return
+	}
+	binary.Write(buf, binary.BigEndian, t)
+	binary.Write(buf, binary.BigEndian, uint16(len(value)))
+	buf.Write(value)
+}
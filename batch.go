@@ -0,0 +1,21 @@
+package FoxMNDP
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+)
+
+// parseJob is a single received packet queued for a parse worker.
+type parseJob struct {
+	packet []byte
+	addr   net.Addr
+}
+
+// reader batches packet reads off a socket. On platforms with recvmmsg
+// support it's backed by ipv4.PacketConn.ReadBatch (see batch_batched.go);
+// elsewhere it falls back to one ReadFrom per call (see batch_fallback.go).
+type reader interface {
+	ReadBatch(msgs []ipv4.Message) (int, error)
+	Close() error
+}
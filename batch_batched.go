@@ -0,0 +1,28 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package FoxMNDP
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+)
+
+// batchReader reads multiple packets per syscall via recvmmsg.
+type batchReader struct {
+	pc *ipv4.PacketConn
+}
+
+// newReader wraps conn in a reader appropriate for the current platform.
+// batchSize is unused here beyond what the caller already sized msgs to.
+func newReader(conn net.PacketConn, batchSize int) reader {
+	return &batchReader{pc: ipv4.NewPacketConn(conn)}
+}
+
+func (r *batchReader) ReadBatch(msgs []ipv4.Message) (int, error) {
+	return r.pc.ReadBatch(msgs, 0)
+}
+
+func (r *batchReader) Close() error {
+	return r.pc.Close()
+}
@@ -0,0 +1,33 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package FoxMNDP
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+)
+
+// fallbackReader emulates ReadBatch with a single ReadFrom per call, for
+// platforms without recvmmsg (notably Windows).
+type fallbackReader struct {
+	conn net.PacketConn
+}
+
+func newReader(conn net.PacketConn, batchSize int) reader {
+	return &fallbackReader{conn: conn}
+}
+
+func (r *fallbackReader) ReadBatch(msgs []ipv4.Message) (int, error) {
+	n, addr, err := r.conn.ReadFrom(msgs[0].Buffers[0])
+	if err != nil {
+		return 0, err
+	}
+	msgs[0].N = n
+	msgs[0].Addr = addr
+	return 1, nil
+}
+
+func (r *fallbackReader) Close() error {
+	return r.conn.Close()
+}
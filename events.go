@@ -0,0 +1,81 @@
+package FoxMNDP
+
+import "net"
+
+// Logger is a minimal structured-logging sink: each level takes a message
+// and an alternating key/value pair list, the same calling convention used
+// by log/slog's Logger (Debug/Info/Warn/Error) and log15-style handlers,
+// so either can be adapted in with a one-line wrapper.
+type Logger interface {
+	Debug(msg string, kv ...interface{})
+	Info(msg string, kv ...interface{})
+	Warn(msg string, kv ...interface{})
+	Error(msg string, kv ...interface{})
+}
+
+// noopLogger is the default Logger when Options.Logger is nil.
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Warn(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
+
+// Event is implemented by every value sent on FoxMNDP.Events. Consumers
+// type-switch on it to react to the specific occurrences they care about.
+type Event interface {
+	isEvent()
+}
+
+// EventStarted is emitted once Start has finished binding its sockets.
+// Update emits EventRebind instead.
+type EventStarted struct {
+	Message string
+}
+
+// EventStopped is emitted when Stop has fully torn the service down.
+type EventStopped struct{}
+
+// EventRebind is emitted when Update has finished replacing every socket.
+type EventRebind struct {
+	Message string
+}
+
+// EventDeviceFound is emitted for every successfully decoded MNDP packet.
+type EventDeviceFound struct {
+	Device Device
+}
+
+// EventParseError is emitted when a received packet could not be decoded.
+// Unlike the old Error channel, it retains the raw bytes and sender so
+// unrecognized or malformed packets (e.g. new TLV types from a newer
+// RouterOS release) can actually be inspected.
+type EventParseError struct {
+	Addr net.Addr
+	Raw  []byte
+	Err  error
+}
+
+func (EventStarted) isEvent()     {}
+func (EventStopped) isEvent()     {}
+func (EventRebind) isEvent()      {}
+func (EventDeviceFound) isEvent() {}
+func (EventParseError) isEvent()  {}
+
+// emit delivers ev on Events and, for EventDeviceFound, also forwards it to
+// the legacy DeviceFound channel so existing consumers keep working
+// unchanged. Both sends are non-blocking: a consumer that only drains one of
+// the two channels (the whole point of keeping DeviceFound around) must not
+// be able to wedge every parse worker once the other channel's buffer fills.
+func (f *FoxMNDP) emit(ev Event) {
+	select {
+	case f.Events <- ev:
+	default:
+	}
+	if found, ok := ev.(EventDeviceFound); ok {
+		select {
+		case f.DeviceFound <- found.Device:
+		default:
+		}
+	}
+}
@@ -7,28 +7,45 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"sync"
 	"time"
+
+	"golang.org/x/net/ipv4"
 )
 
-// MNDP TLV (Type-Length-Value) attribute types.
+// MNDP TLV (Type-Length-Value) attribute types with a built-in decoder; see
+// tlv.go for the extensible registry covering these and any others
+// registered with RegisterTLV.
 const (
-	tlvMACAddress uint16 = 1
-	tlvIdentity   uint16 = 5
-	tlvVersion    uint16 = 7
-	tlvPlatform   uint16 = 8
-	tlvUptime     uint16 = 10
-	tlvBoard      uint16 = 12
+	tlvMACAddress    uint16 = 1
+	tlvIdentity      uint16 = 5
+	tlvVersion       uint16 = 7
+	tlvPlatform      uint16 = 8
+	tlvUptime        uint16 = 10
+	tlvSoftwareID    uint16 = 11
+	tlvBoard         uint16 = 12
+	tlvIPv6Address   uint16 = 15
+	tlvInterfaceName uint16 = 16
 )
 
 // Device represents a discovered Mikrotik device.
 type Device struct {
-	IPAddress  string         // IP address of the device
-	MACAddress net.HardwareAddr // MAC address of the device
-	Identity   string         // Configured device identity
-	Version    string         // RouterOS version
-	Platform   string         // Device platform (e.g., "MikroTik")
-	Uptime     time.Duration  // Device uptime
-	Board      string         // Hardware board model (e.g., "RB4011iGS+")
+	IPAddress     string           // IP address of the device
+	MACAddress    net.HardwareAddr // MAC address of the device
+	Identity      string           // Configured device identity
+	Version       string           // RouterOS version
+	Platform      string           // Device platform (e.g., "MikroTik")
+	Uptime        time.Duration    // Device uptime
+	Board         string           // Hardware board model (e.g., "RB4011iGS+")
+	Interface     string           // Local interface the packet was received on
+	InterfaceName string           // Remote interface name the device announced itself on
+	IPv6Address   net.IP           // Device's IPv6 address, if advertised
+	SoftwareID    string           // RouterOS software ID
+
+	// Unknown holds the raw value of every TLV attribute that has no
+	// registered decoder, keyed by its type, so newer/vendor-specific
+	// attributes aren't silently discarded.
+	Unknown map[uint16][]byte
 }
 
 // Options holds configuration for the discovery service.
@@ -36,25 +53,64 @@ type Options struct {
 	Port    int    // UDP port to listen on. Default: 5678.
 	Host    string // Host IP address to bind to. Default: "0.0.0.0".
 	Version string // Network protocol. "udp4" or "udp6". Default: "udp4".
+
+	// Interfaces restricts listening to the named interfaces, one socket
+	// per interface. Empty means listen on every interface via a single
+	// wildcard-bound socket. The announcer does not use this list; see
+	// AnnounceOptions.Interfaces.
+	Interfaces []string
+
+	// Logger receives structured diagnostic logs (bind failures, read
+	// errors, rebinds, ...). Default: a no-op logger.
+	Logger Logger
+
+	// BatchSize is how many packets are pulled from the socket per
+	// ReadBatch call on platforms that support recvmmsg. Default: 32.
+	BatchSize int
+
+	// ParseWorkers is the size of the fixed worker pool that parses
+	// received packets. Default: 4.
+	ParseWorkers int
 }
 
 // FoxMNDP is the main discovery service client.
 type FoxMNDP struct {
+	// mu serializes Start/Update/Stop (and the hot-plug handling in
+	// watchInterfaces) against each other so two rebinds, or a rebind and a
+	// hot-plug event, can never race over the same sockets.
+	mu sync.Mutex
+
+	// wg tracks every listen() loop (and its worker pool) plus the
+	// interface watcher, so Stop can join all of them before closing the
+	// event channels they send on.
+	wg sync.WaitGroup
+
 	options Options
-	conn    net.PacketConn
+	logger  Logger
+
+	connsMu sync.Mutex
+	conns   map[string]net.PacketConn // keyed by interface name, "" for the wildcard socket
+
+	// connGen is bumped, per name, every time that name's socket is
+	// replaced or torn down (by Update or removeConn). listen() captures
+	// the value current when it started and compares against it on every
+	// read error, so it can tell "my socket was intentionally closed out
+	// from under me" apart from "my socket (which is still the live one
+	// for this name) hit a real network error" — scoping generation to the
+	// name keeps one interface's churn from masking another's errors.
+	connGen map[string]uint64
 
 	// Channels for event communication
-	DeviceFound chan Device
-	Error       chan error
-	Started     chan string
-	Stopped     chan struct{}
+	Events           chan Event
+	DeviceFound      chan Device // Deprecated: kept for compatibility; see EventDeviceFound on Events.
+	InterfaceChanged chan InterfaceEvent
 
 	stopChan chan struct{} // Internal signal channel for stopping
 }
 
-// New creates a new FoxMNDP service instance.
-func New(options Options) (*FoxMNDP, error) {
-	// Apply default values
+// applyDefaults fills in zero-valued Options fields, shared by New and
+// Update so a rebind defaults exactly the way a fresh instance would.
+func applyDefaults(options Options) Options {
 	if options.Port == 0 {
 		options.Port = 5678
 	}
@@ -70,67 +126,271 @@ func New(options Options) (*FoxMNDP, error) {
 		options.Host = "::"
 	}
 
+	if options.BatchSize == 0 {
+		options.BatchSize = 32
+	}
+	if options.ParseWorkers == 0 {
+		options.ParseWorkers = 4
+	}
+
+	return options
+}
+
+// New creates a new FoxMNDP service instance.
+func New(options Options) (*FoxMNDP, error) {
+	options = applyDefaults(options)
+
+	logger := options.Logger
+	if logger == nil {
+		logger = noopLogger{}
+	}
+
 	return &FoxMNDP{
-		options:     options,
-		DeviceFound: make(chan Device, 10), // Buffered channels to avoid blocking
-		Error:       make(chan error, 5),
-		Started:     make(chan string, 1),
-		Stopped:     make(chan struct{}, 1),
-		stopChan:    make(chan struct{}),
+		options:          options,
+		logger:           logger,
+		conns:            make(map[string]net.PacketConn),
+		connGen:          make(map[string]uint64),
+		Events:           make(chan Event, 20), // Buffered channels to avoid blocking
+		DeviceFound:      make(chan Device, 10),
+		InterfaceChanged: make(chan InterfaceEvent, 10),
+		stopChan:         make(chan struct{}),
 	}, nil
 }
 
-// Start begins listening for MNDP packets in a new goroutine.
+// Start begins listening for MNDP packets in a new goroutine, one socket
+// per matching interface (or a single wildcard socket if Options.Interfaces
+// is empty).
 func (f *FoxMNDP) Start() {
-	addr := net.JoinHostPort(f.options.Host, strconv.Itoa(f.options.Port))
-	conn, err := net.ListenPacket(f.options.Version, addr)
-	if err != nil {
-		// Send a fatal error if we can't bind
-		f.Error <- fmt.Errorf("failed to bind to %s: %w", addr, err)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.options.Interfaces) == 0 {
+		addr := net.JoinHostPort(f.options.Host, strconv.Itoa(f.options.Port))
+		conn, err := net.ListenPacket(f.options.Version, addr)
+		if err != nil {
+			// Log a fatal error if we can't bind
+			f.logger.Error("failed to bind", "addr", addr, "err", err)
+			return
+		}
+		f.addConn("", conn)
+	} else {
+		ifaces := resolveInterfaces(f.options.Interfaces, f.logger)
+		for _, ifi := range ifaces {
+			conn, err := bindInterface(ifi, f.options)
+			if err != nil {
+				f.logger.Error("failed to bind to interface", "interface", ifi.Name, "err", err)
+				continue
+			}
+			f.addConn(ifi.Name, conn)
+		}
+	}
+
+	msg := fmt.Sprintf("FoxMNDP listener started on %d socket(s)", len(f.conns))
+	f.logger.Info(msg)
+	f.emit(EventStarted{Message: msg})
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.watchInterfaces()
+	}()
+}
+
+// addConn registers conn under name and starts a listen loop for it, bumping
+// that name's generation so any listen loop still winding down from a prior
+// socket under the same name knows it's been superseded. Callers must hold
+// f.mu. If the service is already stopping, conn is closed unused instead.
+func (f *FoxMNDP) addConn(name string, conn net.PacketConn) {
+	select {
+	case <-f.stopChan:
+		conn.Close()
 		return
+	default:
 	}
-	f.conn = conn
 
-	f.Started <- fmt.Sprintf("FoxMNDP listener started on %s", conn.LocalAddr().String())
+	f.connsMu.Lock()
+	f.conns[name] = conn
+	f.connGen[name]++
+	gen := f.connGen[name]
+	f.connsMu.Unlock()
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.listen(name, conn, gen)
+	}()
+}
 
-	go f.listen()
+// removeConn tears down the socket bound to the named interface, if any,
+// bumping its generation so the departing listen loop exits quietly instead
+// of reporting its own socket being closed as an error. Callers must hold
+// f.mu.
+func (f *FoxMNDP) removeConn(name string) {
+	f.connsMu.Lock()
+	conn, ok := f.conns[name]
+	if ok {
+		delete(f.conns, name)
+		f.connGen[name]++
+	}
+	f.connsMu.Unlock()
+
+	if ok {
+		conn.Close()
+	}
 }
 
-// Stop gracefully shuts down the discovery service.
+// Stop gracefully shuts down the discovery service. Unlike Update, this is
+// final: once stopped, a FoxMNDP value cannot be restarted. It waits for
+// every listen loop, its worker pool, and the interface watcher to actually
+// exit before closing the event channels, so none of them can panic sending
+// on a channel Stop just closed out from under them.
 func (f *FoxMNDP) Stop() {
+	f.mu.Lock()
+
 	// Ensure stop is idempotent
 	select {
 	case <-f.stopChan:
 		// Already stopping or stopped
+		f.mu.Unlock()
 		return
 	default:
-		close(f.stopChan)
-		if f.conn != nil {
-			f.conn.Close() // This will unblock the ReadFrom call in listen()
+	}
+	close(f.stopChan)
+	f.closeConns() // This will unblock the ReadFrom/ReadBatch calls in listen()
+
+	// Release f.mu before joining the watcher: it may be sitting inside
+	// handleInterfaceEvent waiting on this same lock to report a hot-plug
+	// event it read just before stopChan closed. Holding f.mu across
+	// wg.Wait() would deadlock against it. handleInterfaceEvent re-checks
+	// stopChan right after acquiring f.mu, so it'll return immediately
+	// once it gets the lock back.
+	f.mu.Unlock()
+
+	f.wg.Wait() // Join every listen loop/worker pool and the watcher
+
+	f.logger.Info("FoxMNDP stopped")
+	f.emit(EventStopped{})
+
+	// Clean up channels
+	close(f.DeviceFound)
+	close(f.Events)
+	close(f.InterfaceChanged)
+}
+
+// closeConns closes and forgets every active socket, without touching the
+// event channels. Used by both Stop (which closes the channels right
+// after) and Update (which doesn't).
+func (f *FoxMNDP) closeConns() {
+	f.connsMu.Lock()
+	for name, conn := range f.conns {
+		conn.Close()
+		delete(f.conns, name)
+		f.connGen[name]++
+	}
+	f.connsMu.Unlock()
+}
+
+// Update atomically rebinds the service to a new Options: every existing
+// socket is closed and replaced according to opts, without closing Events,
+// DeviceFound, or any other event channel, and without recreating the
+// FoxMNDP value. It's meant for long-running supervisors reacting to a
+// config reload (e.g. SIGHUP) or to interfaces appearing/disappearing.
+func (f *FoxMNDP) Update(opts Options) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	select {
+	case <-f.stopChan:
+		return errors.New("FoxMNDP: cannot Update a stopped service")
+	default:
+	}
+
+	opts = applyDefaults(opts)
+
+	f.closeConns()
+
+	f.options = opts
+	if opts.Logger != nil {
+		f.logger = opts.Logger
+	}
+
+	if len(opts.Interfaces) == 0 {
+		addr := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
+		conn, err := net.ListenPacket(opts.Version, addr)
+		if err != nil {
+			return fmt.Errorf("failed to bind to %s: %w", addr, err)
+		}
+		f.addConn("", conn)
+	} else {
+		ifaces := resolveInterfaces(opts.Interfaces, f.logger)
+		for _, ifi := range ifaces {
+			conn, err := bindInterface(ifi, opts)
+			if err != nil {
+				f.logger.Error("failed to bind to interface", "interface", ifi.Name, "err", err)
+				continue
+			}
+			f.addConn(ifi.Name, conn)
 		}
-		f.Stopped <- struct{}{}
-		
-		// Clean up channels
-		close(f.DeviceFound)
-		close(f.Error)
-		close(f.Started)
-		close(f.Stopped)
 	}
+
+	msg := fmt.Sprintf("FoxMNDP rebound with %d socket(s)", len(f.conns))
+	f.logger.Info(msg)
+	f.emit(EventRebind{Message: msg})
+	return nil
 }
 
-// listen is the main loop that reads packets from the connection.
-func (f *FoxMNDP) listen() {
-	buf := make([]byte, 1500) // Standard MTU size
+// listen is the main loop that reads packets from a single interface's
+// connection. Packets are pulled off the socket in batches via reader and
+// handed to a fixed-size worker pool, rather than spawning a goroutine per
+// packet. gen is the generation this socket was opened under; it lets the
+// loop tell a final Stop() apart from a socket closed mid-rebind by Update,
+// in which case it exits quietly since a new listen loop already took over.
+func (f *FoxMNDP) listen(name string, conn net.PacketConn, gen uint64) {
+	r := newReader(conn, f.options.BatchSize)
+	defer r.Close()
+
+	jobs := make(chan parseJob, f.options.BatchSize)
+	var workers sync.WaitGroup
+	for i := 0; i < f.options.ParseWorkers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				f.parsePacket(job.packet, job.addr, name)
+			}
+		}()
+	}
+	defer func() {
+		close(jobs)
+		workers.Wait()
+	}()
+
+	msgs := make([]ipv4.Message, f.options.BatchSize)
+	for i := range msgs {
+		msgs[i].Buffers = [][]byte{make([]byte, 1500)} // Standard MTU size
+	}
+
 	for {
-		n, rinfo, err := f.conn.ReadFrom(buf)
+		n, err := r.ReadBatch(msgs)
 		if err != nil {
 			select {
 			case <-f.stopChan:
 				// Expected error on Stop()
 				return
 			default:
+				f.connsMu.Lock()
+				stale := gen != f.connGen[name]
+				f.connsMu.Unlock()
+
+				if stale {
+					// This socket was closed on purpose, by Update() to make
+					// way for a new generation or by removeConn() when its
+					// interface went away, so there's nothing to report.
+					return
+				}
+
 				// Unexpected network error
-				f.Error <- fmt.Errorf("failed to read from socket: %w", err)
+				f.logger.Error("failed to read from socket", "interface", name, "err", err)
 				if errors.Is(err, net.ErrClosed) {
 					return // Exit loop if connection is closed
 				}
@@ -138,22 +398,24 @@ func (f *FoxMNDP) listen() {
 			}
 		}
 
-		// Copy the buffer to avoid data race
-		packet := make([]byte, n)
-		copy(packet, buf[:n])
+		for i := 0; i < n; i++ {
+			raw := msgs[i].Buffers[0][:msgs[i].N]
+
+			// Copy the buffer to avoid data race with the next ReadBatch.
+			packet := make([]byte, len(raw))
+			copy(packet, raw)
 
-		// Parse the packet in a new goroutine to avoid
-		// blocking the listener loop.
-		go f.parsePacket(packet, rinfo)
+			jobs <- parseJob{packet: packet, addr: msgs[i].Addr}
+		}
 	}
 }
 
 // parsePacket decodes a raw MNDP packet and sends the result.
-func (f *FoxMNDP) parsePacket(buffer []byte, rinfo net.Addr) {
+func (f *FoxMNDP) parsePacket(buffer []byte, rinfo net.Addr, ifaceName string) {
 	// Recover from panics during parsing (e.g., malformed packet)
 	defer func() {
 		if r := recover(); r != nil {
-			f.Error <- fmt.Errorf("panic while parsing packet: %v", r)
+			f.parseError(buffer, rinfo, fmt.Errorf("panic while parsing packet: %v", r))
 		}
 	}()
 
@@ -167,6 +429,7 @@ func (f *FoxMNDP) parsePacket(buffer []byte, rinfo net.Addr) {
 	ipAddr, _, _ := net.SplitHostPort(rinfo.String())
 	device := Device{
 		IPAddress: ipAddr,
+		Interface: ifaceName,
 	}
 
 	// Read TLV (Type-Length-Value) attributes
@@ -175,55 +438,55 @@ func (f *FoxMNDP) parsePacket(buffer []byte, rinfo net.Addr) {
 
 		// Read Type (Big Endian)
 		if err := binary.Read(reader, binary.BigEndian, &tlvType); err != nil {
-			f.Error <- fmt.Errorf("failed to read TLV type: %w", err)
+			f.parseError(buffer, rinfo, fmt.Errorf("failed to read TLV type: %w", err))
 			return
 		}
 
 		// Read Length (Big Endian)
 		if err := binary.Read(reader, binary.BigEndian, &tlvLength); err != nil {
-			f.Error <- fmt.Errorf("failed to read TLV length: %w", err)
+			f.parseError(buffer, rinfo, fmt.Errorf("failed to read TLV length: %w", err))
 			return
 		}
 
 		// Check for corrupt packet
 		if reader.Len() < int(tlvLength) {
-			f.Error <- fmt.Errorf("corrupt packet: expected length %d, have %d", tlvLength, reader.Len())
+			f.parseError(buffer, rinfo, fmt.Errorf("corrupt packet: expected length %d, have %d", tlvLength, reader.Len()))
 			return
 		}
 
 		// Read Value
 		value := make([]byte, tlvLength)
 		if _, err := reader.Read(value); err != nil {
-			f.Error <- fmt.Errorf("failed to read TLV value: %w", err)
+			f.parseError(buffer, rinfo, fmt.Errorf("failed to read TLV value: %w", err))
 			return
 		}
 
-		// Assign value based on type
-		switch tlvType {
-		case tlvMACAddress:
-			device.MACAddress = net.HardwareAddr(value)
-
-		case tlvIdentity:
-			device.Identity = string(value)
-
-		case tlvVersion:
-			device.Version = string(value)
-
-		case tlvPlatform:
-			device.Platform = string(value)
-
-		case tlvBoard:
-			device.Board = string(value)
-
-		case tlvUptime:
-			if len(value) == 4 {
-				// Uptime is a 4-byte Little Endian integer
-				uptimeSeconds := binary.LittleEndian.Uint32(value)
-				device.Uptime = time.Duration(uptimeSeconds) * time.Second
+		// Decode via the registered decoder for this type, falling back to
+		// capturing the raw value verbatim so unrecognized/vendor-specific
+		// attributes aren't silently discarded.
+		if reg, ok := lookupTLV(tlvType); ok {
+			if err := reg.decode(value, &device); err != nil {
+				f.parseError(buffer, rinfo, fmt.Errorf("failed to decode TLV %d (%s): %w", tlvType, reg.name, err))
+				return
+			}
+		} else {
+			if device.Unknown == nil {
+				device.Unknown = make(map[uint16][]byte)
 			}
+			raw := make([]byte, len(value))
+			copy(raw, value)
+			device.Unknown[tlvType] = raw
 		}
 	}
 
 	// Send the fully populated device struct
-	f.DeviceFound <- device
+	f.emit(EventDeviceFound{Device: device})
+}
+
+// parseError logs and emits a decode failure, retaining the raw packet and
+// sender so malformed or unrecognized payloads can actually be inspected
+// (the old Error channel only carried a formatted string).
+func (f *FoxMNDP) parseError(raw []byte, addr net.Addr, err error) {
+	f.logger.Error("failed to parse packet", "addr", addr, "err", err)
+	f.emit(EventParseError{Addr: addr, Raw: raw, Err: err})
 }
@@ -0,0 +1,201 @@
+package FoxMNDP
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// newTestFoxMNDP builds a FoxMNDP with buffered channels big enough for a
+// single packet's worth of events, without actually binding any sockets.
+func newTestFoxMNDP(t *testing.T) *FoxMNDP {
+	t.Helper()
+	f, err := New(Options{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return f
+}
+
+var testAddr net.Addr = &net.UDPAddr{IP: net.ParseIP("192.168.88.1"), Port: 5678}
+
+func TestEncodeDeviceParsePacketRoundTrip(t *testing.T) {
+	want := Device{
+		MACAddress:    net.HardwareAddr{0x00, 0x0c, 0x42, 0x11, 0x22, 0x33},
+		Identity:      "MikroTik",
+		Version:       "7.14 (stable)",
+		Platform:      "MikroTik",
+		Uptime:        90 * time.Second,
+		Board:         "RB4011iGS+",
+		InterfaceName: "ether1",
+		IPv6Address:   net.ParseIP("fe80::1"),
+		SoftwareID:    "ABCD-1234",
+	}
+
+	packet := encodeDevice(want)
+
+	f := newTestFoxMNDP(t)
+	f.parsePacket(packet, testAddr, "eth0")
+
+	select {
+	case ev := <-f.Events:
+		found, ok := ev.(EventDeviceFound)
+		if !ok {
+			t.Fatalf("got event %T, want EventDeviceFound", ev)
+		}
+		got := found.Device
+
+		if got.Identity != want.Identity {
+			t.Errorf("Identity = %q, want %q", got.Identity, want.Identity)
+		}
+		if got.Version != want.Version {
+			t.Errorf("Version = %q, want %q", got.Version, want.Version)
+		}
+		if got.Platform != want.Platform {
+			t.Errorf("Platform = %q, want %q", got.Platform, want.Platform)
+		}
+		if got.Uptime != want.Uptime {
+			t.Errorf("Uptime = %v, want %v", got.Uptime, want.Uptime)
+		}
+		if got.Board != want.Board {
+			t.Errorf("Board = %q, want %q", got.Board, want.Board)
+		}
+		if got.InterfaceName != want.InterfaceName {
+			t.Errorf("InterfaceName = %q, want %q", got.InterfaceName, want.InterfaceName)
+		}
+		if !got.IPv6Address.Equal(want.IPv6Address) {
+			t.Errorf("IPv6Address = %v, want %v", got.IPv6Address, want.IPv6Address)
+		}
+		if got.SoftwareID != want.SoftwareID {
+			t.Errorf("SoftwareID = %q, want %q", got.SoftwareID, want.SoftwareID)
+		}
+		if got.MACAddress.String() != want.MACAddress.String() {
+			t.Errorf("MACAddress = %v, want %v", got.MACAddress, want.MACAddress)
+		}
+		if got.Interface != "eth0" {
+			t.Errorf("Interface = %q, want %q", got.Interface, "eth0")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event emitted")
+	}
+}
+
+func TestParsePacketUnknownTLVPassthrough(t *testing.T) {
+	const unknownType uint16 = 9999
+
+	var packet []byte
+	packet = append(packet, 0x00, 0x00, 0x00, 0x00) // 4-byte header
+
+	var buf []byte
+	buf = append(buf, byte(unknownType>>8), byte(unknownType&0xff))
+	value := []byte{0xde, 0xad, 0xbe, 0xef}
+	buf = append(buf, byte(len(value)>>8), byte(len(value)))
+	buf = append(buf, value...)
+	packet = append(packet, buf...)
+
+	f := newTestFoxMNDP(t)
+	f.parsePacket(packet, testAddr, "eth0")
+
+	select {
+	case ev := <-f.Events:
+		found, ok := ev.(EventDeviceFound)
+		if !ok {
+			t.Fatalf("got event %T, want EventDeviceFound", ev)
+		}
+		got, ok := found.Device.Unknown[unknownType]
+		if !ok {
+			t.Fatalf("Unknown[%d] not populated", unknownType)
+		}
+		if string(got) != string(value) {
+			t.Errorf("Unknown[%d] = %x, want %x", unknownType, got, value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event emitted")
+	}
+}
+
+func TestParsePacketMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		packet  []byte
+		wantErr bool // true if a parse error should be emitted; false if the packet should be silently ignored
+	}{
+		{
+			name:    "empty",
+			packet:  nil,
+			wantErr: false,
+		},
+		{
+			name:    "header only, no TLVs",
+			packet:  []byte{0x00, 0x00, 0x00, 0x00},
+			wantErr: false,
+		},
+		{
+			name:    "header plus partial TLV header",
+			packet:  []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			wantErr: false,
+		},
+		{
+			name: "TLV claims more value bytes than remain",
+			packet: []byte{
+				0x00, 0x00, 0x00, 0x00, // 4-byte header
+				0x00, 0x01, // TLV type 1 (mac-address)
+				0x00, 0xff, // claims 255 bytes of value, but none follow
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFoxMNDP(t)
+			f.parsePacket(tt.packet, testAddr, "eth0")
+
+			select {
+			case ev := <-f.Events:
+				if !tt.wantErr {
+					t.Fatalf("got unexpected event %T", ev)
+				}
+				if _, ok := ev.(EventParseError); !ok {
+					t.Fatalf("got event %T, want EventParseError", ev)
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.wantErr {
+					t.Fatal("no event emitted, want EventParseError")
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterTLVRoundTrip(t *testing.T) {
+	const customType uint16 = 8888
+	var decoded []byte
+
+	RegisterTLV(customType, "custom", func(value []byte, d *Device) error {
+		decoded = append([]byte(nil), value...)
+		return nil
+	})
+
+	var packet []byte
+	packet = append(packet, 0x00, 0x00, 0x00, 0x00)
+	value := []byte("hello")
+	packet = append(packet, byte(customType>>8), byte(customType&0xff))
+	packet = append(packet, byte(len(value)>>8), byte(len(value)))
+	packet = append(packet, value...)
+
+	f := newTestFoxMNDP(t)
+	f.parsePacket(packet, testAddr, "eth0")
+
+	select {
+	case ev := <-f.Events:
+		if _, ok := ev.(EventDeviceFound); !ok {
+			t.Fatalf("got event %T, want EventDeviceFound", ev)
+		}
+		if string(decoded) != string(value) {
+			t.Errorf("decoded = %q, want %q", decoded, value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event emitted")
+	}
+}
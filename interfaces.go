@@ -0,0 +1,166 @@
+package FoxMNDP
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// InterfaceEvent describes an interface appearing or disappearing while
+// FoxMNDP is running, as reported on FoxMNDP.InterfaceChanged.
+type InterfaceEvent struct {
+	Name string // Interface name, e.g. "eth0".
+	Up   bool   // true if the interface just became usable, false if it was removed.
+}
+
+// resolveInterfaces turns a list of interface names into net.Interface
+// values, logging and skipping ones that can't be found rather than failing
+// outright so a typo in one name doesn't take down every other socket.
+func resolveInterfaces(names []string, logger Logger) []net.Interface {
+	ifaces := make([]net.Interface, 0, len(names))
+	for _, name := range names {
+		ifi, err := net.InterfaceByName(name)
+		if err != nil {
+			logger.Error("unknown interface, skipping", "interface", name, "err", err)
+			continue
+		}
+		ifaces = append(ifaces, *ifi)
+	}
+	return ifaces
+}
+
+// bindInterface opens a socket for opts.Version/Port bound to ifi, joining
+// the relevant multicast group where applicable. Binding itself is
+// platform-specific (SO_BINDTODEVICE on Linux, IP_BOUND_IF on BSD/Darwin);
+// see bindToDevice in the platform-specific interfaces_*.go files.
+func bindInterface(ifi net.Interface, opts Options) (net.PacketConn, error) {
+	addr := net.JoinHostPort("", strconv.Itoa(opts.Port))
+
+	conn, err := net.ListenPacket(opts.Version, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open socket: %w", err)
+	}
+
+	if err := bindToDevice(conn, ifi.Name); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to bind socket to %s: %w", ifi.Name, err)
+	}
+
+	switch opts.Version {
+	case "udp4":
+		p := ipv4.NewPacketConn(conn)
+		group := net.IPv4(224, 0, 0, 1)
+		if err := p.JoinGroup(&ifi, &net.UDPAddr{IP: group}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to join %s on %s: %w", group, ifi.Name, err)
+		}
+	case "udp6":
+		p := ipv6.NewPacketConn(conn)
+		if err := p.JoinGroup(&ifi, &net.UDPAddr{IP: ipv6LinkLocalMulticast}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to join %s on %s: %w", ipv6LinkLocalMulticast, ifi.Name, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// watchInterfaces runs for the lifetime of the service, reacting to
+// interfaces as they're added or removed: a newly up interface named in
+// Options.Interfaces is bound and starts listening, and a removed one is
+// torn down, all without restarting the service. Every event is also
+// forwarded to InterfaceChanged for callers who just want to observe. The
+// actual detection mechanism is platform-specific: netlink on Linux, a
+// route socket on BSD/Darwin, and polling elsewhere (see runInterfaceWatcher
+// in the platform-specific interfaces_*.go files).
+func (f *FoxMNDP) watchInterfaces() {
+	events := make(chan InterfaceEvent, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.runInterfaceWatcher(events)
+	}()
+
+	for {
+		select {
+		case <-f.stopChan:
+			// Drain until runInterfaceWatcher actually exits so it can
+			// always make forward progress on its send into events,
+			// rather than leaking the goroutine.
+			for {
+				select {
+				case <-done:
+					return
+				case <-events:
+				}
+			}
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+
+			f.handleInterfaceEvent(ev)
+
+			select {
+			case f.InterfaceChanged <- ev:
+			default:
+				// Don't let a slow/absent consumer stall hot-plug handling.
+			}
+		}
+	}
+}
+
+// handleInterfaceEvent binds or tears down the socket for a named
+// interface in response to it appearing or disappearing. It's a no-op
+// unless Options.Interfaces was used to restrict listening to specific
+// interfaces in the first place. It takes f.mu so it can never race with a
+// concurrent Update or Stop over the same conns/connGen maps.
+func (f *FoxMNDP) handleInterfaceEvent(ev InterfaceEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	select {
+	case <-f.stopChan:
+		return
+	default:
+	}
+
+	if !containsString(f.options.Interfaces, ev.Name) {
+		return
+	}
+
+	f.connsMu.Lock()
+	_, have := f.conns[ev.Name]
+	f.connsMu.Unlock()
+
+	switch {
+	case ev.Up && !have:
+		ifi, err := net.InterfaceByName(ev.Name)
+		if err != nil {
+			f.logger.Error("failed to look up hot-plugged interface", "interface", ev.Name, "err", err)
+			return
+		}
+		conn, err := bindInterface(*ifi, f.options)
+		if err != nil {
+			f.logger.Error("failed to bind to hot-plugged interface", "interface", ev.Name, "err", err)
+			return
+		}
+		f.addConn(ev.Name, conn)
+
+	case !ev.Up && have:
+		f.removeConn(ev.Name)
+	}
+}
+
+// containsString reports whether s is present in list.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package FoxMNDP
+
+import (
+	"net"
+
+	"golang.org/x/net/route"
+	"golang.org/x/sys/unix"
+)
+
+// runInterfaceWatcher reads RTM_IFINFO messages off a routing socket and
+// forwards interface up/down transitions as InterfaceEvent values on events
+// until Stop is called. This is shared across the whole BSD family
+// (including Darwin); see interfaces_darwin.go / interfaces_bsd_other.go
+// for where bindToDevice diverges between them.
+func (f *FoxMNDP) runInterfaceWatcher(events chan<- InterfaceEvent) {
+	fd, err := unix.Socket(unix.AF_ROUTE, unix.SOCK_RAW, unix.AF_UNSPEC)
+	if err != nil {
+		f.logger.Error("failed to open routing socket", "err", err)
+		return
+	}
+	defer unix.Close(fd)
+
+	go func() {
+		<-f.stopChan
+		unix.Close(fd)
+	}()
+
+	buf := make([]byte, 2048)
+	for {
+		n, err := unix.Read(fd, buf)
+		if err != nil {
+			select {
+			case <-f.stopChan:
+				return
+			default:
+				f.logger.Error("failed to read from routing socket", "err", err)
+				return
+			}
+		}
+
+		msgs, err := route.ParseRIB(route.RIBTypeRoute, buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, m := range msgs {
+			ifm, ok := m.(*route.InterfaceMessage)
+			if !ok {
+				continue
+			}
+			ifi, err := net.InterfaceByIndex(ifm.Index)
+			if err != nil {
+				continue
+			}
+			events <- InterfaceEvent{
+				Name: ifi.Name,
+				Up:   ifi.Flags&net.FlagUp != 0,
+			}
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build freebsd || netbsd || openbsd
+
+package FoxMNDP
+
+import "net"
+
+// bindToDevice is a no-op on these BSDs: unlike Darwin's IP_BOUND_IF, there
+// is no portable per-socket "bind to this interface" knob across
+// FreeBSD/NetBSD/OpenBSD (FreeBSD's IP_BINDANY solves a different problem
+// and needs root). Multicast group membership on the joined interface still
+// scopes reception.
+func bindToDevice(conn net.PacketConn, name string) error {
+	return nil
+}
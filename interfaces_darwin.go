@@ -0,0 +1,38 @@
+//go:build darwin
+
+package FoxMNDP
+
+import (
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// bindToDevice restricts conn to a single interface using IP_BOUND_IF,
+// Darwin's equivalent of Linux's SO_BINDTODEVICE. unix.IP_BOUND_IF is only
+// defined on darwin; other BSDs have no equivalent per-socket knob (see
+// interfaces_bsd_other.go).
+func bindToDevice(conn net.PacketConn, name string) error {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return err
+	}
+
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_BOUND_IF, ifi.Index)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
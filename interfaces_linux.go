@@ -0,0 +1,57 @@
+//go:build linux
+
+package FoxMNDP
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// bindToDevice restricts conn to a single interface using SO_BINDTODEVICE,
+// so broadcasts are only sent/received on that link rather than whichever
+// one the kernel's routing table happens to pick.
+func bindToDevice(conn net.PacketConn, name string) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptString(int(fd), unix.SOL_SOCKET, unix.SO_BINDTODEVICE, name)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// runInterfaceWatcher subscribes to netlink link updates and forwards them
+// as InterfaceEvent values on events until Stop is called.
+func (f *FoxMNDP) runInterfaceWatcher(events chan<- InterfaceEvent) {
+	updates := make(chan netlink.LinkUpdate)
+	done := make(chan struct{})
+	defer close(done)
+
+	if err := netlink.LinkSubscribe(updates, done); err != nil {
+		f.logger.Error("failed to subscribe to netlink link updates", "err", err)
+		return
+	}
+
+	for {
+		select {
+		case <-f.stopChan:
+			return
+		case update := <-updates:
+			name := update.Attrs().Name
+			up := update.Attrs().Flags&net.FlagUp != 0
+			events <- InterfaceEvent{Name: name, Up: up}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package FoxMNDP
+
+import (
+	"net"
+	"time"
+)
+
+// interfacePollInterval is how often runInterfaceWatcher re-lists
+// interfaces on platforms without a push-based notification mechanism.
+const interfacePollInterval = 5 * time.Second
+
+// bindToDevice is a no-op on platforms (notably Windows) where per-socket
+// interface binding isn't exposed through the standard library; multicast
+// group membership on the joined interface still scopes reception.
+func bindToDevice(conn net.PacketConn, name string) error {
+	return nil
+}
+
+// runInterfaceWatcher polls net.Interfaces (backed by GetAdaptersAddresses
+// on Windows) at interfacePollInterval and diffs successive snapshots,
+// sending an InterfaceEvent on events for every interface that appeared,
+// disappeared, or changed its up/down state.
+func (f *FoxMNDP) runInterfaceWatcher(events chan<- InterfaceEvent) {
+	seen := make(map[string]bool) // name -> up
+
+	ticker := time.NewTicker(interfacePollInterval)
+	defer ticker.Stop()
+
+	for {
+		ifaces, err := net.Interfaces()
+		if err != nil {
+			f.logger.Error("failed to list interfaces", "err", err)
+		} else {
+			current := make(map[string]bool, len(ifaces))
+			for _, ifi := range ifaces {
+				up := ifi.Flags&net.FlagUp != 0
+				current[ifi.Name] = up
+
+				wasUp, existed := seen[ifi.Name]
+				if !existed || wasUp != up {
+					events <- InterfaceEvent{Name: ifi.Name, Up: up}
+				}
+			}
+			for name := range seen {
+				if _, ok := current[name]; !ok {
+					events <- InterfaceEvent{Name: name, Up: false}
+				}
+			}
+			seen = current
+		}
+
+		select {
+		case <-f.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package FoxMNDP
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// TLVDecoder decodes the raw value of a single TLV attribute into device.
+type TLVDecoder func(value []byte, device *Device) error
+
+// tlvEncoder serializes a TLV attribute's value out of device, used by the
+// announcer to build outgoing packets. ok is false if device has nothing to
+// contribute for this type, in which case the attribute is omitted.
+type tlvEncoder func(device *Device) (value []byte, ok bool)
+
+type tlvRegistration struct {
+	name   string
+	decode TLVDecoder
+	encode tlvEncoder // nil for attributes registered via the public RegisterTLV
+}
+
+var (
+	tlvRegistryMu sync.RWMutex
+	tlvRegistry   = map[uint16]tlvRegistration{}
+)
+
+// RegisterTLV adds (or replaces) a decoder for TLV type t, so packets
+// carrying attributes this package doesn't otherwise recognize (newer
+// RouterOS versions and vendor extensions both add these over time) can be
+// decoded without forking FoxMNDP. name is descriptive only, used in error
+// messages. Types registered this way aren't emitted by the announcer,
+// since there's no way to serialize a value back out of Device for them;
+// see the built-in registrations in this file for that round trip.
+func RegisterTLV(t uint16, name string, decode func(value []byte, device *Device) error) {
+	tlvRegistryMu.Lock()
+	defer tlvRegistryMu.Unlock()
+	tlvRegistry[t] = tlvRegistration{name: name, decode: decode}
+}
+
+// registerBuiltinTLV is RegisterTLV plus an encoder, used only for the
+// attributes this package decodes out of the box so the announcer can
+// serialize them back.
+func registerBuiltinTLV(t uint16, name string, decode TLVDecoder, encode tlvEncoder) {
+	tlvRegistryMu.Lock()
+	defer tlvRegistryMu.Unlock()
+	tlvRegistry[t] = tlvRegistration{name: name, decode: decode, encode: encode}
+}
+
+// lookupTLV returns the registration for t, if any.
+func lookupTLV(t uint16) (tlvRegistration, bool) {
+	tlvRegistryMu.RLock()
+	defer tlvRegistryMu.RUnlock()
+	reg, ok := tlvRegistry[t]
+	return reg, ok
+}
+
+// encodableTLVTypes returns every registered type with an encoder, in
+// ascending order so encodeDevice produces a stable packet layout.
+func encodableTLVTypes() []uint16 {
+	tlvRegistryMu.RLock()
+	defer tlvRegistryMu.RUnlock()
+
+	types := make([]uint16, 0, len(tlvRegistry))
+	for t, reg := range tlvRegistry {
+		if reg.encode != nil {
+			types = append(types, t)
+		}
+	}
+	for i := 1; i < len(types); i++ {
+		for j := i; j > 0 && types[j-1] > types[j]; j-- {
+			types[j-1], types[j] = types[j], types[j-1]
+		}
+	}
+	return types
+}
+
+func init() {
+	registerBuiltinTLV(tlvMACAddress, "mac-address",
+		func(value []byte, d *Device) error {
+			d.MACAddress = net.HardwareAddr(value)
+			return nil
+		},
+		func(d *Device) ([]byte, bool) {
+			if len(d.MACAddress) == 0 {
+				return nil, false
+			}
+			return d.MACAddress, true
+		},
+	)
+
+	registerBuiltinTLV(tlvIdentity, "identity",
+		func(value []byte, d *Device) error { d.Identity = string(value); return nil },
+		func(d *Device) ([]byte, bool) {
+			if d.Identity == "" {
+				return nil, false
+			}
+			return []byte(d.Identity), true
+		},
+	)
+
+	registerBuiltinTLV(tlvVersion, "version",
+		func(value []byte, d *Device) error { d.Version = string(value); return nil },
+		func(d *Device) ([]byte, bool) {
+			if d.Version == "" {
+				return nil, false
+			}
+			return []byte(d.Version), true
+		},
+	)
+
+	registerBuiltinTLV(tlvPlatform, "platform",
+		func(value []byte, d *Device) error { d.Platform = string(value); return nil },
+		func(d *Device) ([]byte, bool) {
+			if d.Platform == "" {
+				return nil, false
+			}
+			return []byte(d.Platform), true
+		},
+	)
+
+	registerBuiltinTLV(tlvBoard, "board",
+		func(value []byte, d *Device) error { d.Board = string(value); return nil },
+		func(d *Device) ([]byte, bool) {
+			if d.Board == "" {
+				return nil, false
+			}
+			return []byte(d.Board), true
+		},
+	)
+
+	registerBuiltinTLV(tlvUptime, "uptime",
+		func(value []byte, d *Device) error {
+			if len(value) != 4 {
+				// Historically ignored rather than treated as a decode
+				// error: some implementations have been seen padding this
+				// field oddly.
+				return nil
+			}
+			d.Uptime = time.Duration(binary.LittleEndian.Uint32(value)) * time.Second
+			return nil
+		},
+		func(d *Device) ([]byte, bool) {
+			value := make([]byte, 4)
+			binary.LittleEndian.PutUint32(value, uint32(d.Uptime.Seconds()))
+			return value, true
+		},
+	)
+
+	registerBuiltinTLV(tlvInterfaceName, "interface-name",
+		func(value []byte, d *Device) error { d.InterfaceName = string(value); return nil },
+		func(d *Device) ([]byte, bool) {
+			if d.InterfaceName == "" {
+				return nil, false
+			}
+			return []byte(d.InterfaceName), true
+		},
+	)
+
+	registerBuiltinTLV(tlvIPv6Address, "ipv6-address",
+		func(value []byte, d *Device) error {
+			if len(value) != net.IPv6len {
+				return nil
+			}
+			d.IPv6Address = net.IP(value)
+			return nil
+		},
+		func(d *Device) ([]byte, bool) {
+			ip := d.IPv6Address.To16()
+			if ip == nil {
+				return nil, false
+			}
+			return ip, true
+		},
+	)
+
+	registerBuiltinTLV(tlvSoftwareID, "software-id",
+		func(value []byte, d *Device) error { d.SoftwareID = string(value); return nil },
+		func(d *Device) ([]byte, bool) {
+			if d.SoftwareID == "" {
+				return nil, false
+			}
+			return []byte(d.SoftwareID), true
+		},
+	)
+}